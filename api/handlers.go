@@ -0,0 +1,211 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// repoRow mirrors the columns selected off the repos table.
+type repoRow struct {
+	ID          int    `json:"id"`
+	Owner       string `json:"owner"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	HTMLURL     string `json:"htmlurl"`
+	Homepage    string `json:"homepage"`
+	Topics      string `json:"topics"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+	UID         int    `json:"uid"`
+	Source      string `json:"source"`
+}
+
+// sortColumns whitelists what sort_column may reference so it can be
+// interpolated into the query without opening it up to injection.
+var sortColumns = map[string]bool{
+	"id":         true,
+	"owner":      true,
+	"name":       true,
+	"category":   true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+const defaultLimit = 50
+
+func (s *Server) handleListRepos(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit := defaultLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	offset := 0
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+		offset = n
+	}
+
+	sortColumn := "id"
+	if v := q.Get("sort_column"); v != "" {
+		if !sortColumns[v] {
+			http.Error(w, "invalid sort_column", http.StatusBadRequest)
+			return
+		}
+		sortColumn = v
+	}
+
+	sortOrder := "asc"
+	if v := strings.ToLower(q.Get("sort_order")); v != "" {
+		if v != "asc" && v != "desc" {
+			http.Error(w, "invalid sort_order", http.StatusBadRequest)
+			return
+		}
+		sortOrder = v
+	}
+
+	where := []string{"r.deleted_at IS NULL"}
+	var args []any
+	if v := q.Get("category"); v != "" {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf("r.category = $%d", len(args)))
+	}
+	if v := q.Get("updatedSince"); v != "" {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf("r.updated_at >= $%d", len(args)))
+	}
+	if v := q.Get("source"); v != "" {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf("r.source = $%d", len(args)))
+	}
+
+	query := "SELECT r.id, r.owner, r.name, r.category, r.description, r.html_url, r.homepage, r.topics, r.created_at, r.updated_at, r.uid, r.source FROM repos r"
+	if v := q.Get("topic"); v != "" {
+		args = append(args, v)
+		query += " JOIN repo_topics rt ON rt.repo_id = r.id JOIN topics t ON t.id = rt.topic_id"
+		where = append(where, fmt.Sprintf("t.name = $%d", len(args)))
+	}
+	query += " WHERE " + strings.Join(where, " AND ")
+	query += fmt.Sprintf(" ORDER BY r.%s %s", sortColumn, sortOrder)
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := s.db.QueryContext(r.Context(), query, args...)
+	if err != nil {
+		http.Error(w, "query failed", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	repos, err := scanRepoRows(rows)
+	if err != nil {
+		http.Error(w, "scan failed", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, repos)
+}
+
+func (s *Server) handleGetRepoByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := s.db.QueryContext(r.Context(),
+		"SELECT id, owner, name, category, description, html_url, homepage, topics, created_at, updated_at, uid, source FROM repos WHERE id = $1 AND deleted_at IS NULL",
+		id)
+	if err != nil {
+		http.Error(w, "query failed", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	repos, err := scanRepoRows(rows)
+	if err != nil {
+		http.Error(w, "scan failed", http.StatusInternalServerError)
+		return
+	}
+	if len(repos) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, repos[0])
+}
+
+func (s *Server) handleGetRepoByOwnerName(w http.ResponseWriter, r *http.Request) {
+	owner := r.PathValue("owner")
+	name := r.PathValue("name")
+
+	rows, err := s.db.QueryContext(r.Context(),
+		"SELECT id, owner, name, category, description, html_url, homepage, topics, created_at, updated_at, uid, source FROM repos WHERE owner = $1 AND name = $2 AND deleted_at IS NULL",
+		owner, name)
+	if err != nil {
+		http.Error(w, "query failed", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	repos, err := scanRepoRows(rows)
+	if err != nil {
+		http.Error(w, "scan failed", http.StatusInternalServerError)
+		return
+	}
+	if len(repos) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, repos[0])
+}
+
+func scanRepoRows(rows interface {
+	Next() bool
+	Scan(dest ...any) error
+}) ([]repoRow, error) {
+	var repos []repoRow
+	for rows.Next() {
+		var rr repoRow
+		if err := rows.Scan(
+			&rr.ID,
+			&rr.Owner,
+			&rr.Name,
+			&rr.Category,
+			&rr.Description,
+			&rr.HTMLURL,
+			&rr.Homepage,
+			&rr.Topics,
+			&rr.CreatedAt,
+			&rr.UpdatedAt,
+			&rr.UID,
+			&rr.Source,
+		); err != nil {
+			return nil, err
+		}
+		repos = append(repos, rr)
+	}
+	return repos, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, "encode failed", http.StatusInternalServerError)
+	}
+}
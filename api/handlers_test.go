@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleListReposRejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "negative limit", query: "limit=-1"},
+		{name: "non-numeric limit", query: "limit=abc"},
+		{name: "negative offset", query: "offset=-1"},
+		{name: "unknown sort_column", query: "sort_column=deleted_at"},
+		{name: "invalid sort_order", query: "sort_order=sideways"},
+	}
+
+	s := &Server{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/repos?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			s.handleListRepos(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestSortColumnsWhitelist(t *testing.T) {
+	for _, column := range []string{"id", "owner", "name", "category", "created_at", "updated_at"} {
+		if !sortColumns[column] {
+			t.Errorf("expected %q to be a whitelisted sort column", column)
+		}
+	}
+	if sortColumns["deleted_at"] {
+		t.Error("deleted_at should not be a whitelisted sort column")
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Server exposes the repos table over HTTP so downstream consumers don't
+// have to read the one-shot JSON dump off stdout.
+type Server struct {
+	db  *sql.DB
+	srv *http.Server
+}
+
+// New builds a Server listening on addr, backed by pool.
+func New(pool *sql.DB, addr string) *Server {
+	s := &Server{db: pool}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos", s.handleListRepos)
+	mux.HandleFunc("GET /repos/{id}", s.handleGetRepoByID)
+	mux.HandleFunc("GET /repos/{owner}/{name}", s.handleGetRepoByOwnerName)
+
+	s.srv = &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}
+
+	return s
+}
+
+// Start blocks serving HTTP until the server is shut down.
+func (s *Server) Start() error {
+	log.Printf("api: listening on %s", s.srv.Addr)
+	if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully drains in-flight requests before returning.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/google/go-github/v61/github"
+
+	"github.com/permalik/permalik-tertiary/source"
+)
+
+// sourceEntry is one entry of the sources config file.
+type sourceEntry struct {
+	Type    string `json:"type"`
+	Owner   string `json:"owner"`
+	BaseURL string `json:"base_url"`
+	IsOrg   bool   `json:"is_org"`
+	IsGroup bool   `json:"is_group"`
+}
+
+type sourcesFile struct {
+	Sources []sourceEntry `json:"sources"`
+}
+
+const defaultSourcesConfigPath = "sources.json"
+
+// loadSourceConfigs reads the sources config (env SOURCES_CONFIG, default
+// "sources.json") and builds a Provider per entry. If the file doesn't
+// exist, it falls back to the single GitHub source this tool always
+// supported, so an unconfigured deployment keeps working.
+func loadSourceConfigs(gc *github.Client) ([]sourceConfig, error) {
+	path := os.Getenv("SOURCES_CONFIG")
+	if path == "" {
+		path = defaultSourcesConfigPath
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []sourceConfig{
+			{name: "github", owner: "permalik", provider: source.NewGitHubProvider(gc, false)},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sources config %s: %w", path, err)
+	}
+
+	var cfg sourcesFile
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing sources config %s: %w", path, err)
+	}
+
+	var configs []sourceConfig
+	for _, e := range cfg.Sources {
+		cfg, err := newSourceConfig(gc, e)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+
+	return configs, nil
+}
+
+func newSourceConfig(gc *github.Client, e sourceEntry) (sourceConfig, error) {
+	switch e.Type {
+	case "github":
+		return sourceConfig{name: "github", owner: e.Owner, provider: source.NewGitHubProvider(gc, e.IsOrg)}, nil
+
+	case "gitea":
+		p, err := source.NewGiteaProvider(e.BaseURL, os.Getenv("GITEA_TOKEN"))
+		if err != nil {
+			return sourceConfig{}, fmt.Errorf("configuring gitea source for %s: %w", e.Owner, err)
+		}
+		return sourceConfig{name: "gitea", owner: e.Owner, provider: p}, nil
+
+	case "gitlab":
+		p, err := source.NewGitLabProvider(e.BaseURL, os.Getenv("GITLAB_TOKEN"), e.IsGroup)
+		if err != nil {
+			return sourceConfig{}, fmt.Errorf("configuring gitlab source for %s: %w", e.Owner, err)
+		}
+		return sourceConfig{name: "gitlab", owner: e.Owner, provider: p}, nil
+
+	default:
+		return sourceConfig{}, fmt.Errorf("unknown source type %q", e.Type)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/pgtype"
+
+	"github.com/permalik/permalik-tertiary/source"
+)
+
+func ping(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
+	if err := pool.PingContext(ctx); err != nil {
+		log.Fatalf("unable to connect to database:\n%v", err)
+	}
+}
+
+// defaultCategory is used when a repo's description has no "category:
+// description" shape to split on, so a bare description isn't swallowed
+// into the category column.
+const defaultCategory = "uncategorized"
+
+// splitCategory splits a "category: description" shaped description into
+// its two parts. If description has no such shape, it falls back to
+// defaultCategory rather than swallowing the bare description into the
+// category column.
+func splitCategory(description string) (category, rest string) {
+	category, rest, found := strings.Cut(description, ":")
+	if !found {
+		return defaultCategory, description
+	}
+	return category, rest
+}
+
+// upsertRepo inserts r, or, if a row with the same (sourceName, uid)
+// already exists, updates it in place, then replaces its topic
+// associations. This replaces the old drop/recreate cycle so repo
+// history survives partial fetch failures across runs. Everything
+// happens in one transaction so a repo row never ends up with a stale
+// or partial topic set. It reports whether the row was newly inserted,
+// and, for an existing row, whether updated_at actually advanced, so
+// callers can track sync metrics and digest an accurate "changed" set
+// instead of treating every already-known repo as updated.
+func upsertRepo(ctx context.Context, sourceName string, r source.Repo) (inserted, changed bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ownerBefore, nameAfter, _ := strings.Cut(r.FullName, "/")
+	owner := ownerBefore
+	name := nameAfter
+
+	category, description := splitCategory(r.Description)
+
+	var topics string
+	for _, v := range r.Topics {
+		if len(topics) < 1 {
+			topics = v
+		} else {
+			topics = fmt.Sprintf("%s,%s", topics, v)
+		}
+	}
+
+	createdAt := r.CreatedAt.Format("2006-01-02")
+	updatedAt := r.UpdatedAt.Format("2006-01-02")
+
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return false, false, fmt.Errorf("failed beginning upsert tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+    WITH existing AS (
+        SELECT updated_at FROM repos WHERE source = $11 AND uid = $10
+    )
+    INSERT INTO repos (
+        owner,
+        name,
+        category,
+        description,
+        html_url,
+        homepage,
+        topics,
+        created_at,
+        updated_at,
+        uid,
+        source
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    ON CONFLICT (source, uid) DO UPDATE SET
+        owner = excluded.owner,
+        name = excluded.name,
+        category = excluded.category,
+        description = excluded.description,
+        html_url = excluded.html_url,
+        homepage = excluded.homepage,
+        topics = excluded.topics,
+        updated_at = excluded.updated_at,
+        deleted_at = NULL
+    RETURNING id, (xmax = 0) AS inserted, (SELECT updated_at FROM existing);
+    `
+
+	var repoID int
+	var prevUpdatedAt sql.NullString
+	if err := tx.QueryRowContext(ctx, query,
+		owner,
+		name,
+		category,
+		description,
+		r.HTMLURL,
+		r.Homepage,
+		topics,
+		createdAt,
+		updatedAt,
+		r.ID,
+		sourceName).Scan(&repoID, &inserted, &prevUpdatedAt); err != nil {
+		return false, false, fmt.Errorf("failed executing upsert: %w", err)
+	}
+	changed = inserted || !prevUpdatedAt.Valid || prevUpdatedAt.String != updatedAt
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM repo_topics WHERE repo_id = $1", repoID); err != nil {
+		return false, false, fmt.Errorf("failed clearing repo_topics: %w", err)
+	}
+
+	for _, topic := range r.Topics {
+		var topicID int
+		err := tx.QueryRowContext(ctx, `
+            INSERT INTO topics (name) VALUES ($1)
+            ON CONFLICT (name) DO UPDATE SET name = excluded.name
+            RETURNING id;
+            `, topic).Scan(&topicID)
+		if err != nil {
+			return false, false, fmt.Errorf("failed upserting topic: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO repo_topics (repo_id, topic_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			repoID, topicID); err != nil {
+			return false, false, fmt.Errorf("failed linking repo_topics: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, false, fmt.Errorf("failed committing upsert tx: %w", err)
+	}
+
+	return inserted, changed, nil
+}
+
+// markDeleted soft-deletes any repo row from sourceName whose uid wasn't
+// present in the latest fetch, rather than truncating the table.
+func markDeleted(ctx context.Context, sourceName string, fetchedUIDs []int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// pgx v3's stdlib driver implements neither NamedValueChecker nor
+	// ColumnConverter, so database/sql would otherwise hand a bare
+	// []int64 to driver.DefaultParameterConverter, which rejects slices
+	// outright. pgtype.Int8Array implements driver.Valuer, so
+	// database/sql calls its Value() method directly instead.
+	var uids pgtype.Int8Array
+	if err := uids.Set(fetchedUIDs); err != nil {
+		return fmt.Errorf("failed encoding fetched uids: %w", err)
+	}
+
+	_, err := pool.ExecContext(ctx,
+		"UPDATE repos SET deleted_at = now() WHERE deleted_at IS NULL AND source = $1 AND uid != ALL($2::bigint[])",
+		sourceName, &uids)
+	if err != nil {
+		return fmt.Errorf("failed marking deleted repos: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestSplitCategory(t *testing.T) {
+	tests := []struct {
+		name            string
+		description     string
+		wantCategory    string
+		wantDescription string
+	}{
+		{
+			name:            "category and description",
+			description:     "tooling: a CLI for managing repos",
+			wantCategory:    "tooling",
+			wantDescription: " a CLI for managing repos",
+		},
+		{
+			name:            "no separator falls back to default category",
+			description:     "a CLI for managing repos",
+			wantCategory:    defaultCategory,
+			wantDescription: "a CLI for managing repos",
+		},
+		{
+			name:            "empty description falls back to default category",
+			description:     "",
+			wantCategory:    defaultCategory,
+			wantDescription: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, description := splitCategory(tt.description)
+			if category != tt.wantCategory {
+				t.Errorf("category = %q, want %q", category, tt.wantCategory)
+			}
+			if description != tt.wantDescription {
+				t.Errorf("description = %q, want %q", description, tt.wantDescription)
+			}
+		})
+	}
+}
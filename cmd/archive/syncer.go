@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/permalik/permalik-tertiary/notify"
+	"github.com/permalik/permalik-tertiary/source"
+)
+
+// sourceConfig pairs a Provider with the owner to list and the name
+// stored in the repos.source column, so the same GitHub/Gitea/GitLab
+// reconciliation logic works across every configured source.
+type sourceConfig struct {
+	name     string
+	owner    string
+	provider source.Provider
+}
+
+// Syncer periodically pulls repos from every configured source and
+// reconciles them into the repos table, replacing the old one-shot
+// fetch-then-exit flow.
+type Syncer struct {
+	sources []sourceConfig
+	emailer notify.Emailer
+}
+
+// NewSyncer builds a Syncer over sources, emailing emailer a digest of
+// what changed after each run.
+func NewSyncer(sources []sourceConfig, emailer notify.Emailer) *Syncer {
+	return &Syncer{sources: sources, emailer: emailer}
+}
+
+// Run ticks every interval until ctx is canceled, running one sync
+// immediately rather than waiting out the first interval.
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) {
+	s.syncOnce(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.syncOnce(ctx)
+		}
+	}
+}
+
+type syncMetrics struct {
+	startedAt     time.Time
+	finishedAt    time.Time
+	reposFetched  int
+	reposInserted int
+	reposUpdated  int
+	errors        int
+}
+
+func (s *Syncer) syncOnce(ctx context.Context) {
+	metrics := syncMetrics{startedAt: time.Now()}
+	var digest notify.Digest
+
+	defer func() {
+		metrics.finishedAt = time.Now()
+		recordSyncRun(ctx, metrics)
+
+		if err := s.emailer.Send(ctx, digest); err != nil {
+			log.Printf("sync: sending digest email failed: %v", err)
+		}
+	}()
+
+	for _, src := range s.sources {
+		s.syncSource(ctx, src, &metrics, &digest)
+	}
+}
+
+func (s *Syncer) syncSource(ctx context.Context, src sourceConfig, metrics *syncMetrics, digest *notify.Digest) {
+	repos, err := src.provider.ListRepos(ctx, src.owner)
+	if err != nil {
+		log.Printf("sync: fetching %s/%s failed: %v", src.name, src.owner, err)
+		metrics.errors++
+		return
+	}
+	if len(repos) == 0 {
+		log.Printf("sync: %s/%s returned no repos, skipping delete reconciliation", src.name, src.owner)
+		return
+	}
+	metrics.reposFetched += len(repos)
+
+	// Collected from repos, not from successful upserts: a repo whose
+	// upsert fails this run still exists upstream and shouldn't be
+	// soft-deleted just because we couldn't write it this time.
+	fetchedUIDs := make([]int64, 0, len(repos))
+	for _, r := range repos {
+		fetchedUIDs = append(fetchedUIDs, r.ID)
+	}
+
+	for _, r := range repos {
+		inserted, changed, err := upsertRepo(ctx, src.name, r)
+		if err != nil {
+			log.Printf("sync: upserting %s/%s failed: %v", src.name, r.FullName, err)
+			metrics.errors++
+			continue
+		}
+		if inserted {
+			metrics.reposInserted++
+			digest.New = append(digest.New, repoSummary(r))
+		} else if changed {
+			metrics.reposUpdated++
+			digest.Updated = append(digest.Updated, repoSummary(r))
+		}
+	}
+
+	if err := markDeleted(ctx, src.name, fetchedUIDs); err != nil {
+		log.Printf("sync: marking deleted repos for %s failed: %v", src.name, err)
+		metrics.errors++
+	}
+}
+
+// repoSummary builds the digest-email view of r, applying the same
+// category/description split upsertRepo uses.
+func repoSummary(r source.Repo) notify.RepoSummary {
+	category, description := splitCategory(r.Description)
+
+	return notify.RepoSummary{
+		Name:        r.Name,
+		Category:    category,
+		Description: description,
+		Homepage:    r.Homepage,
+	}
+}
+
+func recordSyncRun(ctx context.Context, m syncMetrics) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pool.ExecContext(ctx, `
+        INSERT INTO sync_runs (
+            started_at,
+            finished_at,
+            repos_fetched,
+            repos_inserted,
+            repos_updated,
+            errors,
+            duration_ms
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7);
+        `,
+		m.startedAt,
+		m.finishedAt,
+		m.reposFetched,
+		m.reposInserted,
+		m.reposUpdated,
+		m.errors,
+		m.finishedAt.Sub(m.startedAt).Milliseconds())
+	if err != nil {
+		log.Printf("sync: recording sync_runs failed: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+// Package migrate applies versioned SQL files to the database at startup,
+// tracking what's already been applied in a schema_migrations table. This
+// replaces the old drop-table-and-recreate cycle that wiped history on
+// every run.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+const migrationsDir = "migrations"
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Run applies any pending migrations in order, in a single transaction
+// per file, recording each applied version in schema_migrations.
+func Run(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version INT PRIMARY KEY,
+            name VARCHAR(200) NOT NULL,
+            applied_at TIMESTAMP NOT NULL DEFAULT now()
+        )`); err != nil {
+		return fmt.Errorf("migrate: unable to create schema_migrations: %w", err)
+	}
+
+	applied := map[int]bool{}
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("migrate: unable to read schema_migrations: %w", err)
+	}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return fmt.Errorf("migrate: unable to scan version: %w", err)
+		}
+		applied[v] = true
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	pending, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if applied[m.version] {
+			continue
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("migrate: unable to begin tx for %s: %w", m.name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate: applying %s: %w", m.name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+			m.version, m.name); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate: recording %s: %w", m.name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migrate: committing %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: unable to read migrations dir: %w", err)
+	}
+
+	var migrations []migration
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+
+		versionStr, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			return nil, fmt.Errorf("migrate: malformed migration filename %q", e.Name())
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: malformed migration version %q: %w", e.Name(), err)
+		}
+
+		contents, err := migrationFiles.ReadFile(path.Join(migrationsDir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: unable to read %s: %w", e.Name(), err)
+		}
+
+		migrations = append(migrations, migration{
+			version: version,
+			name:    e.Name(),
+			sql:     string(contents),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	return migrations, nil
+}
@@ -0,0 +1,29 @@
+package migrate
+
+import "testing"
+
+func TestLoadMigrationsOrdersByVersion(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("loadMigrations: expected at least one migration")
+	}
+
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version <= migrations[i-1].version {
+			t.Fatalf("migrations out of order: %s (%d) before %s (%d)",
+				migrations[i-1].name, migrations[i-1].version,
+				migrations[i].name, migrations[i].version)
+		}
+	}
+
+	first := migrations[0]
+	if first.version != 1 {
+		t.Errorf("first migration version = %d, want 1", first.version)
+	}
+	if first.sql == "" {
+		t.Errorf("migration %s: sql contents not loaded", first.name)
+	}
+}
@@ -0,0 +1,142 @@
+// Package notify sends a digest email summarizing what a sync run added
+// or updated, fulfilling the old "send email to pm" TODO.
+package notify
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+)
+
+// RepoSummary is the minimal set of fields included in a digest email.
+type RepoSummary struct {
+	Name        string
+	Category    string
+	Description string
+	Homepage    string
+}
+
+// Digest groups the repos that changed in one sync run.
+type Digest struct {
+	New     []RepoSummary
+	Updated []RepoSummary
+}
+
+// Empty reports whether there's nothing worth emailing about.
+func (d Digest) Empty() bool {
+	return len(d.New) == 0 && len(d.Updated) == 0
+}
+
+// Emailer sends a sync digest somewhere.
+type Emailer interface {
+	Send(ctx context.Context, d Digest) error
+}
+
+//go:embed templates/digest.html.tmpl
+var digestTemplateSource string
+
+var digestTemplate = template.Must(template.New("digest").Parse(digestTemplateSource))
+
+// SMTPConfig configures an SMTPEmailer.
+type SMTPConfig struct {
+	Host string
+	User string
+	Pass string
+	From string
+	To   string
+}
+
+// SMTPEmailer sends the digest as an HTML email over SMTP.
+type SMTPEmailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPEmailer builds an SMTPEmailer from cfg.
+func NewSMTPEmailer(cfg SMTPConfig) *SMTPEmailer {
+	return &SMTPEmailer{cfg: cfg}
+}
+
+func (e *SMTPEmailer) Send(ctx context.Context, d Digest) error {
+	if d.Empty() {
+		return nil
+	}
+
+	body, err := renderDigest(d)
+	if err != nil {
+		return fmt.Errorf("notify: rendering digest: %w", err)
+	}
+
+	msg := buildMessage(e.cfg.From, e.cfg.To, body)
+	// SendMail dials Host as "host:port", but PlainAuth checks the bare
+	// host name the client connected to.
+	authHost, _, err := net.SplitHostPort(e.cfg.Host)
+	if err != nil {
+		authHost = e.cfg.Host
+	}
+	auth := smtp.PlainAuth("", e.cfg.User, e.cfg.Pass, authHost)
+
+	if err := smtp.SendMail(e.cfg.Host, auth, e.cfg.From, []string{e.cfg.To}, msg); err != nil {
+		return fmt.Errorf("notify: sending mail: %w", err)
+	}
+	return nil
+}
+
+// DryRunEmailer logs the digest instead of sending it, for local/dev use.
+type DryRunEmailer struct{}
+
+func (DryRunEmailer) Send(ctx context.Context, d Digest) error {
+	if d.Empty() {
+		return nil
+	}
+
+	body, err := renderDigest(d)
+	if err != nil {
+		return fmt.Errorf("notify: rendering digest: %w", err)
+	}
+
+	log.Printf("notify: dry run, would send digest:\n%s", body)
+	return nil
+}
+
+func renderDigest(d Digest) (string, error) {
+	var buf bytes.Buffer
+	if err := digestTemplate.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func buildMessage(from, to, htmlBody string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	buf.WriteString("Subject: permalik-tertiary: repo sync digest\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n")
+	buf.WriteString(htmlBody)
+	return buf.Bytes()
+}
+
+// NewEmailerFromEnv builds an Emailer from SMTP_HOST, SMTP_USER, SMTP_PASS,
+// MAIL_FROM and MAIL_TO. If SMTP_HOST is unset, or MAIL_DRY_RUN is "true",
+// it returns a DryRunEmailer that logs instead of sending.
+func NewEmailerFromEnv() Emailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" || os.Getenv("MAIL_DRY_RUN") == "true" {
+		return DryRunEmailer{}
+	}
+
+	return NewSMTPEmailer(SMTPConfig{
+		Host: host,
+		User: os.Getenv("SMTP_USER"),
+		Pass: os.Getenv("SMTP_PASS"),
+		From: os.Getenv("MAIL_FROM"),
+		To:   os.Getenv("MAIL_TO"),
+	})
+}
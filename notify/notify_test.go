@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDigestIncludesNewAndUpdated(t *testing.T) {
+	d := Digest{
+		New: []RepoSummary{
+			{Name: "repo-a", Category: "tooling", Description: "a new repo", Homepage: "https://example.com/a"},
+		},
+		Updated: []RepoSummary{
+			{Name: "repo-b", Category: "library", Description: "an updated repo"},
+		},
+	}
+
+	out, err := renderDigest(d)
+	if err != nil {
+		t.Fatalf("renderDigest: %v", err)
+	}
+
+	for _, want := range []string{"New repos", "repo-a", "Updated repos", "repo-b", "https://example.com/a"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered digest missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderDigestOmitsEmptySections(t *testing.T) {
+	out, err := renderDigest(Digest{Updated: []RepoSummary{{Name: "repo-b"}}})
+	if err != nil {
+		t.Fatalf("renderDigest: %v", err)
+	}
+
+	if strings.Contains(out, "New repos") {
+		t.Errorf("rendered digest should omit the New repos section when empty:\n%s", out)
+	}
+}
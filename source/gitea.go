@@ -0,0 +1,78 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+const giteaPerPage = 50
+
+// GiteaProvider lists a user's public repos on a self-hosted Gitea
+// instance.
+type GiteaProvider struct {
+	client *gitea.Client
+}
+
+// NewGiteaProvider builds a GiteaProvider against baseURL using token.
+func NewGiteaProvider(baseURL, token string) (*GiteaProvider, error) {
+	client, err := gitea.NewClient(baseURL, gitea.SetToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("gitea: building client: %w", err)
+	}
+
+	return &GiteaProvider{client: client}, nil
+}
+
+// ListRepos walks every page of owner's public repos.
+func (p *GiteaProvider) ListRepos(ctx context.Context, owner string) ([]Repo, error) {
+	var repos []Repo
+
+	page := 1
+	for {
+		list, resp, err := p.client.ListUserRepos(owner, gitea.ListReposOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: giteaPerPage},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gitea: list repos for %s: %w", owner, err)
+		}
+
+		for _, v := range list {
+			if v.Private {
+				continue
+			}
+
+			topics, _, err := p.client.ListRepoTopics(owner, v.Name, gitea.ListRepoTopicsOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("gitea: list topics for %s: %w", v.FullName, err)
+			}
+
+			repos = append(repos, toGiteaRepo(v, topics))
+		}
+
+		if resp == nil || page >= resp.LastPage {
+			break
+		}
+		page++
+	}
+
+	return repos, nil
+}
+
+// toGiteaRepo builds a Repo from v. topics comes from a separate
+// ListRepoTopics call since gitea.Repository doesn't carry topics
+// itself.
+func toGiteaRepo(v *gitea.Repository, topics []string) Repo {
+	return Repo{
+		ID:          v.ID,
+		Name:        v.Name,
+		FullName:    v.FullName,
+		Description: v.Description,
+		HTMLURL:     v.HTMLURL,
+		Homepage:    v.Website,
+		Topics:      topics,
+		CreatedAt:   v.Created,
+		UpdatedAt:   v.Updated,
+	}
+}
@@ -0,0 +1,129 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+)
+
+const (
+	githubListPerPage      = 100
+	githubMaxFetchAttempts = 5
+	githubInitialBackoff   = 1 * time.Second
+)
+
+// GitHubProvider lists an owner's (or org's) public repos from GitHub.
+type GitHubProvider struct {
+	client *github.Client
+	isOrg  bool
+}
+
+// NewGitHubProvider builds a GitHubProvider. isOrg selects whether owner
+// names a user or an organization.
+func NewGitHubProvider(client *github.Client, isOrg bool) *GitHubProvider {
+	return &GitHubProvider{client: client, isOrg: isOrg}
+}
+
+// ListRepos walks every page of owner's public repos, retrying
+// rate-limited pages with exponential backoff instead of failing the
+// whole sync.
+func (p *GitHubProvider) ListRepos(ctx context.Context, owner string) ([]Repo, error) {
+	var repos []Repo
+
+	page := 1
+	for {
+		var (
+			data []*github.Repository
+			resp *github.Response
+		)
+
+		err := githubWithBackoff(ctx, func() error {
+			var innerErr error
+			listOpt := github.ListOptions{Page: page, PerPage: githubListPerPage}
+			if p.isOrg {
+				opts := &github.RepositoryListByOrgOptions{Type: "public", Sort: "created", ListOptions: listOpt}
+				data, resp, innerErr = p.client.Repositories.ListByOrg(ctx, owner, opts)
+			} else {
+				opts := &github.RepositoryListByUserOptions{Type: "public", Sort: "created", ListOptions: listOpt}
+				data, resp, innerErr = p.client.Repositories.ListByUser(ctx, owner, opts)
+			}
+			return innerErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("github: list repos for %s: %w", owner, err)
+		}
+
+		for _, v := range data {
+			repos = append(repos, toGitHubRepo(v))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func toGitHubRepo(v *github.Repository) Repo {
+	createdAt := v.GetCreatedAt()
+	updatedAt := v.GetUpdatedAt()
+
+	return Repo{
+		ID:          v.GetID(),
+		Name:        v.GetName(),
+		FullName:    v.GetFullName(),
+		Description: v.GetDescription(),
+		HTMLURL:     v.GetHTMLURL(),
+		Homepage:    v.GetHomepage(),
+		Topics:      v.Topics,
+		CreatedAt:   createdAt.GetTime().Local(),
+		UpdatedAt:   updatedAt.GetTime().Local(),
+	}
+}
+
+// githubWithBackoff retries fn with exponential backoff when go-github
+// reports a primary or secondary (abuse) rate limit, honoring the
+// reset/retry hints GitHub sends back rather than guessing at a delay.
+func githubWithBackoff(ctx context.Context, fn func() error) error {
+	backoff := githubInitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var rateErr *github.RateLimitError
+		var abuseErr *github.AbuseRateLimitError
+		isRateLimited := errors.As(err, &rateErr) || errors.As(err, &abuseErr)
+		if !isRateLimited || attempt >= githubMaxFetchAttempts {
+			return err
+		}
+
+		wait := backoff
+		switch {
+		case abuseErr != nil && abuseErr.RetryAfter != nil:
+			wait = *abuseErr.RetryAfter
+		case rateErr != nil:
+			if until := time.Until(rateErr.Rate.Reset.Time); until > 0 {
+				wait = until
+			}
+		}
+
+		log.Printf("github: rate limited, retrying in %s (attempt %d/%d)", wait, attempt, githubMaxFetchAttempts)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+	}
+}
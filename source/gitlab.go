@@ -0,0 +1,96 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+const gitlabPerPage = 100
+
+// GitLabProvider lists a user or group's public projects on a self-hosted
+// or gitlab.com instance.
+type GitLabProvider struct {
+	client  *gitlab.Client
+	isGroup bool
+}
+
+// NewGitLabProvider builds a GitLabProvider against baseURL (empty for
+// gitlab.com) using token. isGroup selects whether owner names a group
+// or a user.
+func NewGitLabProvider(baseURL, token string, isGroup bool) (*GitLabProvider, error) {
+	var opts []gitlab.ClientOptionFunc
+	if baseURL != "" {
+		opts = append(opts, gitlab.WithBaseURL(baseURL))
+	}
+
+	client, err := gitlab.NewClient(token, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: building client: %w", err)
+	}
+
+	return &GitLabProvider{client: client, isGroup: isGroup}, nil
+}
+
+// ListRepos walks every page of owner's public projects.
+func (p *GitLabProvider) ListRepos(ctx context.Context, owner string) ([]Repo, error) {
+	var repos []Repo
+
+	page := 1
+	for {
+		var (
+			projects []*gitlab.Project
+			resp     *gitlab.Response
+			err      error
+		)
+
+		listOpt := gitlab.ListOptions{Page: page, PerPage: gitlabPerPage}
+		visibility := gitlab.PublicVisibility
+
+		if p.isGroup {
+			opts := &gitlab.ListGroupProjectsOptions{ListOptions: listOpt, Visibility: &visibility}
+			projects, resp, err = p.client.Groups.ListGroupProjects(owner, opts, gitlab.WithContext(ctx))
+		} else {
+			opts := &gitlab.ListProjectsOptions{ListOptions: listOpt, Visibility: &visibility}
+			projects, resp, err = p.client.Projects.ListUserProjects(owner, opts, gitlab.WithContext(ctx))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gitlab: list projects for %s: %w", owner, err)
+		}
+
+		for _, v := range projects {
+			repos = append(repos, toGitLabRepo(v))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func toGitLabRepo(v *gitlab.Project) Repo {
+	var createdAt, updatedAt time.Time
+	if v.CreatedAt != nil {
+		createdAt = *v.CreatedAt
+	}
+	if v.LastActivityAt != nil {
+		updatedAt = *v.LastActivityAt
+	}
+
+	return Repo{
+		ID:          int64(v.ID),
+		Name:        v.Name,
+		FullName:    v.PathWithNamespace,
+		Description: v.Description,
+		HTMLURL:     v.WebURL,
+		Homepage:    v.WebURL,
+		Topics:      v.Topics,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
@@ -0,0 +1,27 @@
+// Package source abstracts over the git hosts repos can be pulled from,
+// so GitHub isn't the only place the sync can read from.
+package source
+
+import (
+	"context"
+	"time"
+)
+
+// Repo is the common shape every Provider normalizes its host's repo
+// metadata into.
+type Repo struct {
+	ID          int64
+	Name        string
+	FullName    string
+	Description string
+	HTMLURL     string
+	Homepage    string
+	Topics      []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Provider lists the public repos owned by owner on some git host.
+type Provider interface {
+	ListRepos(ctx context.Context, owner string) ([]Repo, error)
+}